@@ -0,0 +1,18 @@
+// Command tryvet reports exported functions that can panic via a
+// try.E-family call without a deferred recovery in the function body. See
+// the internal/tryvet package doc for the full rule.
+//
+// Run it directly, or plug it into go vet:
+//
+//	go vet -vettool=$(which tryvet) ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/xtdlib/try/internal/tryvet"
+)
+
+func main() {
+	singlechecker.Main(tryvet.Analyzer)
+}
@@ -0,0 +1,185 @@
+// Package tryvet implements the tryvet analyzer.
+//
+// The package doc for try warns that "panic handling generally should not
+// cross package boundaries or be an explicit part of an API." tryvet
+// enforces that: it flags exported functions that call a try.E-family
+// function (E, E1, E2, E3, or E4), directly or transitively through
+// another function in the same package, without a deferred try.Handle,
+// try.HandleF, try.Catch, try.CatchF, try.CatchAs, try.F, try.Recover, or
+// try.TestFatal as a top-level statement of the function body, since a
+// panic from try could otherwise escape to the function's callers. Where in
+// the body that defer stands is not checked: a try.E-family call that runs
+// before the defer is reached is not reported.
+//
+// A function can opt out of the check with a "//try:allowpanic" comment
+// directly above its declaration.
+package tryvet
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const doc = `report try.E-family panics that can escape exported functions
+
+The tryvet analyzer flags exported functions that call try.E, try.E1,
+try.E2, try.E3, or try.E4 (directly, or transitively via another function
+in the same package) without a deferred try.Handle, try.HandleF,
+try.Catch, try.CatchF, try.CatchAs, try.F, try.Recover, or try.TestFatal,
+so that a panic from try cannot escape to callers. Guard an intentional
+exception with a "//try:allowpanic" comment directly above the function
+declaration.`
+
+// Analyzer is the tryvet analysis.Analyzer, run by cmd/tryvet (for example
+// via go vet -vettool=$(which tryvet)). Its package is internal, so code
+// outside this module, such as a golangci-lint plugin, cannot import it.
+var Analyzer = &analysis.Analyzer{
+	Name:     "tryvet",
+	Doc:      doc,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+const tryPkgPath = "github.com/xtdlib/try"
+
+var panicFuncs = map[string]bool{
+	"E": true, "E1": true, "E2": true, "E3": true, "E4": true,
+}
+
+// guardFuncs must track the recovery functions actually defined in package
+// try (Handle, HandleF, and Recover are real functions, not the stubs they
+// once were) so the analyzer's advice is actionable.
+var guardFuncs = map[string]bool{
+	"Handle": true, "HandleF": true, "Catch": true, "CatchF": true,
+	"CatchAs": true, "F": true, "Recover": true, "TestFatal": true,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	var decls []*ast.FuncDecl
+	declByObj := map[types.Object]*ast.FuncDecl{}
+	insp.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		decl := n.(*ast.FuncDecl)
+		if decl.Body == nil {
+			return
+		}
+		decls = append(decls, decl)
+		if obj := pass.TypesInfo.Defs[decl.Name]; obj != nil {
+			declByObj[obj] = decl
+		}
+	})
+
+	directPanic := map[*ast.FuncDecl]bool{}
+	calls := map[*ast.FuncDecl][]*ast.FuncDecl{}
+	for _, decl := range decls {
+		ast.Inspect(decl.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			if isTryCall(pass, call, panicFuncs) {
+				directPanic[decl] = true
+				return true
+			}
+			if callee := calleeDecl(pass, call, declByObj); callee != nil {
+				calls[decl] = append(calls[decl], callee)
+			}
+			return true
+		})
+	}
+
+	// mayPanic computes, via fixed-point iteration, whether a decl can
+	// reach a try.E-family call through any chain of same-package calls
+	// to functions that are not themselves guarded (see isGuarded).
+	mayPanic := map[*ast.FuncDecl]bool{}
+	for changed := true; changed; {
+		changed = false
+		for _, decl := range decls {
+			if mayPanic[decl] {
+				continue
+			}
+			if directPanic[decl] {
+				mayPanic[decl] = true
+				changed = true
+				continue
+			}
+			for _, callee := range calls[decl] {
+				if mayPanic[callee] && !isGuarded(pass, callee) {
+					mayPanic[decl] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	for _, decl := range decls {
+		if !decl.Name.IsExported() || !mayPanic[decl] {
+			continue
+		}
+		if allowsPanic(decl) || isGuarded(pass, decl) {
+			continue
+		}
+		pass.Reportf(decl.Pos(), "exported function %s can panic via try.E (directly or transitively) without a deferred try.Handle/HandleF/Catch/CatchF/CatchAs/F/Recover/TestFatal; the panic can escape to callers", decl.Name.Name)
+	}
+	return nil, nil
+}
+
+// isGuarded reports whether decl has a deferred call to one of the
+// guardFuncs directly in its body.
+func isGuarded(pass *analysis.Pass, decl *ast.FuncDecl) bool {
+	for _, stmt := range decl.Body.List {
+		def, ok := stmt.(*ast.DeferStmt)
+		if ok && isTryCall(pass, def.Call, guardFuncs) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsPanic reports whether decl is annotated with a "//try:allowpanic"
+// comment directly above its declaration.
+func allowsPanic(decl *ast.FuncDecl) bool {
+	if decl.Doc == nil {
+		return false
+	}
+	for _, c := range decl.Doc.List {
+		if strings.Contains(c.Text, "//try:allowpanic") {
+			return true
+		}
+	}
+	return false
+}
+
+// isTryCall reports whether call invokes one of names on the try package.
+func isTryCall(pass *analysis.Pass, call *ast.CallExpr, names map[string]bool) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || !names[sel.Sel.Name] {
+		return false
+	}
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return false
+	}
+	return fn.Pkg().Path() == tryPkgPath
+}
+
+// calleeDecl resolves a plain (unqualified) call to a *ast.FuncDecl in the
+// package currently being analyzed, or nil if call is not such a call.
+func calleeDecl(pass *analysis.Pass, call *ast.CallExpr, declByObj map[types.Object]*ast.FuncDecl) *ast.FuncDecl {
+	ident, ok := call.Fun.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	obj := pass.TypesInfo.Uses[ident]
+	if obj == nil {
+		return nil
+	}
+	return declByObj[obj]
+}
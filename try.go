@@ -114,11 +114,11 @@
 //	}
 //
 // Recover is like F, but it supports more complicated error handling
-// by passing the error and runtime frame directly to a function.
+// by passing the error and resolved call stack directly to a function.
 //
 //	func f() {
-//		defer try.Recover(func(err error, frame runtime.Frame) {
-//			// do something useful with err and frame
+//		defer try.Recover(func(err error, frames []runtime.Frame) {
+//			// do something useful with err and frames
 //		})
 //		...
 //	}
@@ -126,18 +126,31 @@ package try
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"reflect"
 	"runtime"
 	"strconv"
+	"strings"
+	"sync"
+	"testing"
 
 	"log/slog"
 )
 
+// maxStackDepth bounds the number of call frames captured per panic.
+// This keeps the fast (non-error) path cheap: runtime.Callers is called
+// once in e(), and resolving the frames into function/file/line info is
+// deferred until Error() or Format() is actually invoked.
+const maxStackDepth = 32
+
 // wrapError wraps an error to ensure that we only recover from errors
 // panicked by this package.
 type wrapError struct {
 	error
-	pc [1]uintptr
+	pc [maxStackDepth]uintptr
+	n  int
 }
 
 func (e wrapError) Catch() error {
@@ -147,7 +160,7 @@ func (e wrapError) Catch() error {
 func (e wrapError) Error() string {
 	// Retrieve the last path segment of the filename.
 	// We avoid using strings.LastIndexByte to keep dependencies small.
-	frames := runtime.CallersFrames(e.pc[:])
+	frames := runtime.CallersFrames(e.pc[:1])
 	frame, _ := frames.Next()
 	file := frame.File
 	for i := len(file) - 1; i >= 0; i-- {
@@ -164,11 +177,101 @@ func (e wrapError) Unwrap() error {
 	return e.error
 }
 
+// frames resolves the captured program counters into runtime.Frame values,
+// skipping frames inside this package itself.
+func (e wrapError) frames() []runtime.Frame {
+	if e.n == 0 {
+		return nil
+	}
+	callerFrames := runtime.CallersFrames(e.pc[:e.n])
+	var out []runtime.Frame
+	for {
+		frame, more := callerFrames.Next()
+		if !strings.HasPrefix(frame.Function, "github.com/xtdlib/try.") {
+			out = append(out, frame)
+		}
+		if !more {
+			break
+		}
+	}
+	return out
+}
+
+// Format implements fmt.Formatter. %s and %v print the same single-line
+// "file:line: msg" form as Error. %+v additionally prints the resolved
+// call stack, one function and file:line per frame, skipping frames
+// inside this package.
+func (e wrapError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		io.WriteString(s, e.Error())
+		if s.Flag('+') {
+			for _, frame := range e.frames() {
+				fmt.Fprintf(s, "\n\t%s\n\t\t%s:%d", frame.Function, frame.File, frame.Line)
+			}
+		}
+	case 's':
+		io.WriteString(s, e.Error())
+	}
+}
+
+// Stack returns the resolved call stack captured when err was panicked by
+// an E function, or nil if err was not (and does not wrap) such an error.
+func Stack(err error) []runtime.Frame {
+	for err != nil {
+		if w, ok := err.(wrapError); ok {
+			return w.frames()
+		}
+		u, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			return nil
+		}
+		err = u.Unwrap()
+	}
+	return nil
+}
+
+var (
+	loggerMu sync.Mutex
+	logger   *slog.Logger
+	logLevel = slog.LevelError
+)
+
+// SetLogger sets the *slog.Logger used by the L family of helpers and by
+// the debug trace emitted when Catch/CatchF/Handle/etc. recover an error.
+// Passing nil restores the default of using slog.Default() at each call.
+func SetLogger(l *slog.Logger) {
+	loggerMu.Lock()
+	defer loggerMu.Unlock()
+	logger = l
+}
+
+// SetLogLevel sets the level used by the L family of helpers. It does not
+// affect the internal debug trace, which is always logged at LevelDebug.
+func SetLogLevel(level slog.Level) {
+	loggerMu.Lock()
+	defer loggerMu.Unlock()
+	logLevel = level
+}
+
+// currentLogger returns the configured logger and log level, falling back
+// to slog.Default() so that a later slog.SetDefault takes effect without
+// requiring a call to SetLogger.
+func currentLogger() (*slog.Logger, slog.Level) {
+	loggerMu.Lock()
+	defer loggerMu.Unlock()
+	if logger != nil {
+		return logger, logLevel
+	}
+	return slog.Default(), logLevel
+}
+
 func r(recovered any, fn func(wrapError)) {
 	switch ex := recovered.(type) {
 	case nil:
 	case wrapError:
-		slog.Default().Debug("try: caught: " + ex.Error())
+		l, _ := currentLogger()
+		l.Debug("try: caught: " + ex.Error())
 		fn(ex)
 	default:
 		panic(ex)
@@ -176,48 +279,111 @@ func r(recovered any, fn func(wrapError)) {
 }
 
 // Recover recovers an error previously panicked with an E function.
-// If it recovers an error, it calls fn with the error and the runtime frame in which it occurred.
-// func Recover(fn func(err error, frame runtime.Frame)) {
-// 	r(recover(), func(w WrapError) {
-// 		frames := runtime.CallersFrames(w.pc[:])
-// 		frame, _ := frames.Next()
-// 		fn(w.error, frame)
-// 	})
-// }
-
-// Catch recovers an error previously panicked with an E function and stores it into errptr.
+// If it recovers an error, it calls fn with the error and the resolved call stack.
+func Recover(fn func(err error, frames []runtime.Frame)) {
+	r(recover(), func(w wrapError) {
+		fn(w.error, w.frames())
+	})
+}
+
+// Handle recovers an error previously panicked with an E function and
+// stores it into errptr.
+func Handle(errptr *error) {
+	r(recover(), func(w wrapError) {
+		// Store w itself, not w.error: w.Unwrap returns w.error, so the
+		// stack captured by w is still reachable via Stack/%+v, and
+		// w.Error() still has the file:line: prefix.
+		*errptr = w
+	})
+}
+
+// HandleF is like Handle, but it calls fn after storing the error into
+// errptr.
+func HandleF(errptr *error, fn func()) {
+	r(recover(), func(w wrapError) {
+		*errptr = w
+		fn()
+	})
+}
+
+// Catch recovers an error previously panicked with an E function and, if it
+// matches T per errors.As, stores it into errptr. Matching by errors.As
+// (rather than a direct type assertion) means Catch also matches errors
+// wrapped by lower layers, e.g. via fmt.Errorf("...: %w", sentinel). If the
+// error does not match, the original wrapError is re-panicked so an outer
+// Catch or Handle can still recover it.
 func Catch[T error](errptr *T) {
-	// 	r(recover(), func(w WrapError) { *errptr = w.error })
 	r(recover(), func(w wrapError) {
-		if e, ok := w.error.(T); ok {
-			*errptr = e
-		} else {
-			panic("try: caught error of wrong type: " + w.error.Error())
+		// If w itself satisfies T (e.g. T is the error interface, the
+		// common case of `var err error; defer try.Catch(&err)`), store w
+		// so its stack stays reachable via Stack/%+v. Otherwise fall back
+		// to errors.As against the underlying error.
+		if wt, ok := any(w).(T); ok {
+			*errptr = wt
+			return
+		}
+		var target T
+		if !errors.As(w.error, &target) {
+			panic(w)
 		}
+		*errptr = target
 	})
 }
 
-// CatchF recovers an error previously panicked with an E function and stores it into errptr.
-// If it recovers an error, it calls fn.
+// CatchF recovers an error previously panicked with an E function and, if it
+// matches T per errors.As, stores it into errptr and calls fn. If the error
+// does not match, the original wrapError is re-panicked so an outer Catch or
+// Handle can still recover it.
 func CatchF[T error](errptr *T, fn func()) {
-	// r(recover(), func(w WrapError) {
-	// 	*errptr = w.error
-	// 	if w.error != nil {
-	// 		fn()
-	// 	}
-	// })
+	r(recover(), func(w wrapError) {
+		if wt, ok := any(w).(T); ok {
+			*errptr = wt
+			fn()
+			return
+		}
+		var target T
+		if !errors.As(w.error, &target) {
+			panic(w)
+		}
+		*errptr = target
+		fn()
+	})
+}
 
+// CatchAs recovers an error previously panicked with an E function and
+// dispatches it to the first handler whose argument matches per errors.As.
+// Each handler must have the shape func(T) or func(T) error for some error
+// type T; handlers are tried in order. If a handler returns a non-nil
+// error, that error is panicked in its place so an outer Catch or Handle
+// can recover it. If no handler matches, the original wrapError is
+// re-panicked.
+func CatchAs(handlers ...any) {
+	errType := reflect.TypeOf((*error)(nil)).Elem()
 	r(recover(), func(w wrapError) {
-		if _, ok := w.error.(T); ok {
-			*errptr = w.error.(T)
-			if w.error != nil {
-				fn()
+		for _, h := range handlers {
+			hv := reflect.ValueOf(h)
+			ht := hv.Type()
+			if ht.Kind() != reflect.Func || ht.NumIn() != 1 {
+				continue
 			}
-		} else {
-			panic("try: caught error of wrong type: " + w.error.Error())
+			argType := ht.In(0)
+			if !argType.Implements(errType) {
+				continue
+			}
+			target := reflect.New(argType)
+			if !errors.As(w.error, target.Interface()) {
+				continue
+			}
+			out := hv.Call([]reflect.Value{target.Elem()})
+			if len(out) == 1 {
+				if err, _ := out[0].Interface().(error); err != nil {
+					e(err)
+				}
+			}
+			return
 		}
+		panic(w)
 	})
-
 }
 
 // F recovers an error previously panicked with an E function, wraps it, and passes it to fn.
@@ -228,12 +394,98 @@ func F(fn func(...any)) {
 }
 
 func e(err error) {
+	if prefix := contextPrefix(); prefix != "" {
+		err = fmt.Errorf("%s: %w", prefix, err)
+	}
 	we := wrapError{error: err}
 	// 3: runtime.Callers, e, E
-	runtime.Callers(3, we.pc[:])
+	we.n = runtime.Callers(3, we.pc[:])
 	panic(we)
 }
 
+// contextStacks holds, per goroutine, the stack of active Wrap messages.
+// Keying by goroutine id (rather than a context.Context) lets Wrap be used
+// without threading a context through every call, at the cost of the
+// small parsing hack in goroutineID.
+var contextStacks sync.Map // int64 goroutine id -> *[]string
+
+func goroutineID() int64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	b := buf[len("goroutine "):n]
+	for i, c := range b {
+		if c == ' ' {
+			b = b[:i]
+			break
+		}
+	}
+	id, _ := strconv.ParseInt(string(b), 10, 64)
+	return id
+}
+
+func pushContext(msg string) {
+	v, _ := contextStacks.LoadOrStore(goroutineID(), new([]string))
+	stack := v.(*[]string)
+	*stack = append(*stack, msg)
+}
+
+func popContext() {
+	id := goroutineID()
+	v, ok := contextStacks.Load(id)
+	if !ok {
+		return
+	}
+	stack := v.(*[]string)
+	if len(*stack) > 0 {
+		*stack = (*stack)[:len(*stack)-1]
+	}
+	if len(*stack) == 0 {
+		contextStacks.Delete(id)
+	}
+}
+
+func contextPrefix() string {
+	v, ok := contextStacks.Load(goroutineID())
+	if !ok {
+		return ""
+	}
+	return strings.Join(*v.(*[]string), ": ")
+}
+
+// Annotate wraps the error pointed to by errptr with a formatted prefix,
+// mirroring the errcontext/annotate pattern from other error libraries.
+// Since defers run in LIFO order, it must be deferred before the Handle
+// (or Catch/CatchF/CatchAs) that populates errptr, so that Annotate runs
+// last, after the error has already been set:
+//
+//	func f() (err error) {
+//		defer try.Annotate(&err, "reading header %d", i)
+//		defer try.Handle(&err)
+//		...
+//	}
+func Annotate(errptr *error, format string, args ...any) {
+	if errptr == nil || *errptr == nil {
+		return
+	}
+	*errptr = fmt.Errorf("%s: %w", fmt.Sprintf(format, args...), *errptr)
+}
+
+// Wrap pushes msg onto a per-goroutine stack of active context messages
+// and returns a function that pops it. Any error panicked via an E
+// function while the context is active has the stack of active messages
+// joined onto it as a prefix, so errors.Is and errors.As still traverse
+// the original cause:
+//
+//	func f() (err error) {
+//		defer try.Handle(&err)
+//		defer try.Wrap("parsing config")()
+//		...
+//	}
+func Wrap(msg string) func() {
+	pushContext(msg)
+	return popContext
+}
+
 // E panics if err is non-nil.
 func E(err error) {
 	if err != nil {
@@ -287,38 +539,113 @@ func f(fn func(...any), w wrapError) {
 	fn(w)
 }
 
+// logError logs err, if non-nil, to the configured logger (see SetLogger)
+// at the configured level (see SetLogLevel), attaching file, line, and
+// function attributes for the caller skip frames above logError itself.
+func logError(err error, skip int) {
+	if err == nil {
+		return
+	}
+	l, level := currentLogger()
+	pc, file, line, _ := runtime.Caller(skip)
+	fn := ""
+	if f := runtime.FuncForPC(pc); f != nil {
+		fn = f.Name()
+	}
+	l.Log(context.Background(), level, err.Error(),
+		slog.String("file", file),
+		slog.Int("line", line),
+		slog.String("function", fn),
+		slog.Any("err", err),
+	)
+}
+
 // L logs error if err is non-nil.
 func L(err error) {
-	if err != nil {
-		slog.Default().Log(context.Background(), slog.LevelError, err.Error())
-	}
+	logError(err, 2)
 }
 
 func L1[A any](a A, err error) A {
-	if err != nil {
-		slog.Default().Log(context.Background(), slog.LevelError, err.Error())
-	}
+	logError(err, 2)
 	return a
 }
 
 func L2[A, B any](a A, b B, err error) (A, B) {
-	if err != nil {
-		slog.Default().Log(context.Background(), slog.LevelError, err.Error())
-	}
+	logError(err, 2)
 	return a, b
 }
 
 func L3[A, B, C any](a A, b B, c C, err error) (A, B, C) {
-	if err != nil {
-		slog.Default().Log(context.Background(), slog.LevelError, err.Error())
-	}
+	logError(err, 2)
 	return a, b, c
 }
 
 func L4[A, B, C, D any](a A, b B, c C, d D, err error) (A, B, C, D) {
-	if err != nil {
-		slog.Default().Log(context.Background(), slog.LevelError, err.Error())
+	logError(err, 2)
+	return a, b, c, d
+}
+
+// Logger logs errors via a specific *slog.Logger, with a fixed set of
+// attrs attached to every log record. Its methods cannot carry their own
+// type parameters (Go does not allow generic methods), so unlike the
+// package-level L family they operate on and return any:
+//
+//	try.LWith(auditLog, slog.String("component", "importer")).L1(parse(line))
+type Logger struct {
+	l     *slog.Logger
+	attrs []slog.Attr
+}
+
+// LWith returns a Logger scoped to l and attrs. A nil l falls back to the
+// logger configured via SetLogger (or slog.Default()).
+func LWith(l *slog.Logger, attrs ...slog.Attr) *Logger {
+	return &Logger{l: l, attrs: attrs}
+}
+
+func (lg *Logger) logError(err error) {
+	if err == nil {
+		return
+	}
+	defaultLogger, level := currentLogger()
+	l := lg.l
+	if l == nil {
+		l = defaultLogger
 	}
+	pc, file, line, _ := runtime.Caller(2)
+	fn := ""
+	if f := runtime.FuncForPC(pc); f != nil {
+		fn = f.Name()
+	}
+	attrs := append([]slog.Attr{
+		slog.String("file", file),
+		slog.Int("line", line),
+		slog.String("function", fn),
+		slog.Any("err", err),
+	}, lg.attrs...)
+	l.LogAttrs(context.Background(), level, err.Error(), attrs...)
+}
+
+func (lg *Logger) L(err error) {
+	lg.logError(err)
+}
+
+func (lg *Logger) L1(a any, err error) any {
+	lg.logError(err)
+	return a
+}
+
+func (lg *Logger) L2(a, b any, err error) (any, any) {
+	lg.logError(err)
+	return a, b
+}
+
+func (lg *Logger) L3(a, b, c any, err error) (any, any, any) {
+	lg.logError(err)
+	return a, b, c
+}
+
+func (lg *Logger) L4(a, b, c, d any, err error) (any, any, any, any) {
+	lg.logError(err)
 	return a, b, c, d
 }
 
@@ -382,3 +709,122 @@ func NotZero[T comparable](a T) {
 		e(fmt.Errorf("try: zero: %+v == %+v", a, b))
 	}
 }
+
+// TestFatal recovers an error previously panicked with an E function and
+// reports it via tb.Fatal, including the file:line of the runtime frame in
+// which it occurred.
+//
+//	func TestFoo(t *testing.T) {
+//		defer try.TestFatal(t)
+//		try.E1(os.Open("nonexistent"))
+//	}
+func TestFatal(tb testing.TB) {
+	r(recover(), func(w wrapError) {
+		tb.Helper()
+		tb.Fatal(w.Error())
+	})
+}
+
+// Asserter checks conditions and reports failures via a testing.TB, or, if
+// constructed with a nil TB, by panicking via an E function so it composes
+// with defer try.Handle(&err). Obtain one with Assert.
+type Asserter struct {
+	tb testing.TB
+}
+
+// Assert returns an Asserter whose methods report failures against tb.
+// Passing a nil tb makes the same methods panic via an E function instead
+// of calling Fatalf, so Assert(nil) composes with defer try.Handle(&err).
+func Assert(tb testing.TB) Asserter {
+	return Asserter{tb: tb}
+}
+
+func (a Asserter) fail(format string, args ...any) {
+	if a.tb == nil {
+		e(fmt.Errorf("try: "+format, args...))
+		return
+	}
+	a.tb.Helper()
+	_, file, line, _ := runtime.Caller(2)
+	a.tb.Fatalf("%s:%d: "+format, append([]any{file, line}, args...)...)
+}
+
+// Equal reports a failure if got != want. got and want must be comparable
+// (slices, maps, and similar types panic on ==); use DeepEqual for those.
+func (a Asserter) Equal(got, want any) {
+	equal, comparable := safeEqual(got, want)
+	if !comparable {
+		a.fail("%T is not comparable with ==; use DeepEqual instead", got)
+		return
+	}
+	if !equal {
+		a.fail("not equal: got %+v, want %+v", got, want)
+	}
+}
+
+// safeEqual reports whether got == want, recovering from the runtime
+// panic that comparing two uncomparable values (e.g. slices or maps) of
+// the same dynamic type would otherwise raise.
+func safeEqual(got, want any) (equal, comparable bool) {
+	defer func() {
+		if recover() != nil {
+			equal, comparable = false, false
+		}
+	}()
+	return got == want, true
+}
+
+// DeepEqual reports a failure if got and want are not reflect.DeepEqual,
+// for types (e.g. slices, maps) that Equal cannot compare.
+func (a Asserter) DeepEqual(got, want any) {
+	if !reflect.DeepEqual(got, want) {
+		a.fail("not deep-equal: got %+v, want %+v", got, want)
+	}
+}
+
+// ErrorIs reports a failure if !errors.Is(err, target).
+func (a Asserter) ErrorIs(err, target error) {
+	if !errors.Is(err, target) {
+		a.fail("error %+v does not wrap target %+v", err, target)
+	}
+}
+
+// ErrorAs reports a failure if !errors.As(err, target). target has the
+// same requirements as errors.As: a non-nil pointer to either a type that
+// implements error, or to any interface type.
+func (a Asserter) ErrorAs(err error, target any) {
+	if !errors.As(err, target) {
+		a.fail("error %+v cannot be assigned to %T", err, target)
+	}
+}
+
+// Len reports a failure if s does not have length n. s must be an array,
+// slice, map, string, or channel.
+func (a Asserter) Len(s any, n int) {
+	if got := reflect.ValueOf(s).Len(); got != n {
+		a.fail("wrong length: got %d, want %d", got, n)
+	}
+}
+
+// True reports a failure if b is false.
+func (a Asserter) True(b bool) {
+	if !b {
+		a.fail("expected true")
+	}
+}
+
+// Nil reports a failure if v is not nil, including a typed nil pointer,
+// slice, map, chan, func, or interface stored in v.
+func (a Asserter) Nil(v any) {
+	if v == nil {
+		return
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice:
+		if rv.IsNil() {
+			return
+		}
+	}
+	a.fail("expected nil, got %+v", v)
+}